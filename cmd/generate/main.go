@@ -0,0 +1,538 @@
+// Command generate is a CLI tool that generates/edits images using a
+// pluggable image-generation backend (Azure OpenAI, vanilla OpenAI,
+// Stability AI, or a local Stable Diffusion / Fooocus-compatible server).
+//
+// Usage:
+//
+//	go run ./cmd/generate -p PROMPT [-i IMAGE] [-b BACKGROUND] [-s SIZE] [-backend NAME]
+//	go run ./cmd/generate -batch manifest.jsonl [-concurrency N]
+//
+// Required (single-item mode):
+//
+//	-p  The text prompt for image generation/editing
+//
+// Optional (single-item mode):
+//
+//	-i  The input (foreground) image file. When omitted, the CLI calls
+//	    the backend's generations endpoint (text-to-image) instead of
+//	    its edits endpoint.
+//	-b  Background image file. When provided, both images are sent to
+//	    the API so the model can composite the foreground onto the background.
+//	-m  Mask image for inpainting (PNG with alpha channel). Must have the
+//	    same dimensions as -i.
+//	-s  Image size (default: 1024x1024)
+//
+// Batch mode:
+//
+//	-batch        JSONL manifest, one {prompt, input, background, mask, size,
+//	              output} object per line. Replaces -p/-i/-b/-m/-s. "output"
+//	              is required when "input" is omitted (text-to-image), since
+//	              concurrent items with no derivable name would otherwise
+//	              collide on a shared default.
+//	-concurrency  Number of items to process at once (default: 4)
+//	-results      Where to write the machine-readable results JSON
+//	              (default: <manifest>.results.json)
+//
+// Shared flags:
+//
+//	-backend          Backend to use: azure (default), openai, stability, or local
+//	-timeout          Maximum time to wait for async/job-based backends, per item (default: 5m)
+//	-cache-dir        Directory for the on-disk generation cache (default: ~/.cache/marketing-asset-agent)
+//	-no-cache         Skip the cache and always call the backend
+//	-response-format  "b64_json" (default) or "url"
+//	-quality          Passed through to the backend (e.g. "standard", "hd")
+//	-style            Passed through to the backend (e.g. "vivid", "natural")
+//	-output           Where to persist generated images: a local directory
+//	                  (default), file://DIR, s3://bucket/prefix, or
+//	                  az://container/prefix
+//
+// In single-item mode, the output file is auto-generated from the
+// foreground image name with "_generated" appended, or "generated.png"
+// when there's no input image.
+// Example: input "assets/cat.png" → output "assets/cat_generated.png"
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"mime"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+
+	"github.com/CloudNativeLinz/marketing-asset-creation-agent/internal/batch"
+	"github.com/CloudNativeLinz/marketing-asset-creation-agent/internal/cache"
+	"github.com/CloudNativeLinz/marketing-asset-creation-agent/internal/imagegen"
+	"github.com/CloudNativeLinz/marketing-asset-creation-agent/internal/outputsink"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		// Just warn, don't fail, in case env vars are set directly
+		fmt.Println("No .env file found")
+	}
+
+	backendName := flag.String("backend", imagegen.EnvOr("IMAGEGEN_BACKEND", "azure"), "Backend to use: azure, openai, stability, or local")
+	prompt := flag.String("p", "", "The text prompt for image generation/editing (required)")
+	inputImage := flag.String("i", "", "The foreground input image file (optional; omit for text-to-image)")
+	bgImage := flag.String("b", "", "Background image file (optional)")
+	maskImage := flag.String("m", "", "Mask image for inpainting (PNG with alpha channel); must match -i's dimensions")
+	size := flag.String("s", "1024x1024", "Image size")
+	timeout := flag.Duration("timeout", 5*time.Minute, "Maximum time to wait for async/job-based backends, per item")
+	cacheDir := flag.String("cache-dir", "", "Directory for the on-disk generation cache (default: ~/.cache/marketing-asset-agent)")
+	noCache := flag.Bool("no-cache", false, "Skip the cache and always call the backend")
+	batchFile := flag.String("batch", "", "JSONL manifest for batch mode; replaces -p/-i/-b/-m/-s")
+	concurrency := flag.Int("concurrency", 4, "Number of batch items to process at once")
+	resultsFile := flag.String("results", "", "Where to write the batch results JSON (default: <manifest>.results.json)")
+	responseFormat := flag.String("response-format", "", `"b64_json" (default) or "url"`)
+	quality := flag.String("quality", "", `Image quality passed through to the backend (e.g. "standard", "hd")`)
+	style := flag.String("style", "", `Image style passed through to the backend (e.g. "vivid", "natural")`)
+	output := flag.String("output", "", "Where to persist generated images: a local directory (default), file://DIR, s3://bucket/prefix, or az://container/prefix")
+	flag.Parse()
+
+	cfg := imagegen.ConfigFromEnv()
+
+	backend, err := imagegen.New(*backendName, cfg)
+	if err != nil {
+		fatalf("Error configuring backend: %v", err)
+	}
+
+	sink, err := outputsink.New(*output)
+	if err != nil {
+		fatalf("Error configuring output: %v", err)
+	}
+
+	var genCache *cache.Cache
+	if !*noCache {
+		genCache = openCache(*cacheDir)
+	}
+
+	g := &generator{
+		backend:        backend,
+		backendName:    *backendName,
+		cfg:            cfg,
+		cache:          genCache,
+		sink:           sink,
+		timeout:        *timeout,
+		responseFormat: *responseFormat,
+		quality:        *quality,
+		style:          *style,
+	}
+
+	if *batchFile != "" {
+		runBatch(g, *batchFile, *size, *concurrency, *resultsFile)
+		return
+	}
+
+	runSingle(g, *prompt, *inputImage, *bgImage, *maskImage, *size)
+}
+
+// runSingle handles the original one-shot CLI invocation.
+func runSingle(g *generator, prompt, inputImage, bgImage, maskImage, size string) {
+	if prompt == "" {
+		fatalf("Error: Prompt is required (-p)\nUsage: generate -p PROMPT [-i IMAGE] [-b BACKGROUND] [-s SIZE]")
+	}
+
+	// Check if the prompt is a file path and read its content if so.
+	if fileInfo, err := os.Stat(prompt); err == nil && !fileInfo.IsDir() {
+		content, err := os.ReadFile(prompt)
+		if err != nil {
+			fatalf("Error reading prompt file: %v", err)
+		}
+		prompt = string(content)
+		fmt.Printf("Loaded prompt from file: %s\n", fileInfo.Name())
+	}
+
+	if inputImage != "" {
+		if _, err := os.Stat(inputImage); os.IsNotExist(err) {
+			fatalf("Error: Input image not found: %s", inputImage)
+		}
+	}
+	if bgImage != "" {
+		if inputImage == "" {
+			fatalf("Error: -b requires -i (a background needs a foreground to composite onto)")
+		}
+		if _, err := os.Stat(bgImage); os.IsNotExist(err) {
+			fatalf("Error: Background image not found: %s", bgImage)
+		}
+	}
+	if maskImage != "" {
+		if inputImage == "" {
+			fatalf("Error: -m requires -i (a mask needs a foreground image to inpaint)")
+		}
+		if _, err := os.Stat(maskImage); os.IsNotExist(err) {
+			fatalf("Error: Mask image not found: %s", maskImage)
+		}
+		if err := validateMaskDimensions(maskImage, inputImage); err != nil {
+			fatalf("Error: %v", err)
+		}
+	}
+
+	item := batch.Item{Prompt: prompt, Input: inputImage, Background: bgImage, Mask: maskImage, Size: size}
+
+	mode := "generations (text-to-image)"
+	if inputImage != "" {
+		mode = "edits"
+	}
+	fmt.Printf("Generating image with %s (%s)...\n", g.backend.Name(), mode)
+	fmt.Printf("Prompt:  %s\n", prompt)
+	if bgImage != "" {
+		fmt.Printf("Background: %s\n", bgImage)
+	}
+	if inputImage != "" {
+		fmt.Printf("Foreground: %s\n", inputImage)
+	}
+	fmt.Printf("Size:    %s\n\n", size)
+
+	outputFile, cacheHit, err := g.generate(context.Background(), item)
+	if err != nil {
+		fatalf("Image generation failed: %v", err)
+	}
+
+	if g.responseFormat == "url" {
+		fmt.Println("✅ Image generation successful")
+		fmt.Printf("Image URL: %s\n", outputFile)
+		return
+	}
+
+	status := "✅ Image generation successful"
+	if cacheHit {
+		status += " (cache hit)"
+	}
+	fmt.Println(status)
+
+	// Remote sinks (s3://, az://) return a URL; only a local path can be
+	// stat-ed for a size.
+	if strings.Contains(outputFile, "://") {
+		fmt.Printf("Image uploaded to %s\n", outputFile)
+		return
+	}
+	info, err := os.Stat(outputFile)
+	if err != nil {
+		fatalf("Error stat-ing output file: %v", err)
+	}
+	fmt.Printf("Image saved to %s\n", outputFile)
+	fmt.Printf("Size: %.2f MB\n", float64(info.Size())/(1024*1024))
+}
+
+// runBatch loads a manifest and processes every item through a bounded
+// worker pool, writing a summary and a machine-readable results file.
+func runBatch(g *generator, manifestPath, defaultSize string, concurrency int, resultsPath string) {
+	items, err := batch.LoadManifest(manifestPath)
+	if err != nil {
+		fatalf("Error loading manifest: %v", err)
+	}
+	for i := range items {
+		if items[i].Size == "" {
+			items[i].Size = defaultSize
+		}
+	}
+	fmt.Printf("Processing %d item(s) from %s with concurrency %d...\n", len(items), manifestPath, concurrency)
+
+	results, summary := batch.Run(context.Background(), items, g.generate, batch.Options{
+		Concurrency: concurrency,
+		MaxRetries:  3,
+		IsRetryable: imagegen.IsRetryable,
+		InitialWait: 2 * time.Second,
+	})
+
+	if resultsPath == "" {
+		resultsPath = manifestPath + ".results.json"
+	}
+	resultsJSON, err := json.MarshalIndent(struct {
+		Summary batch.Summary  `json:"summary"`
+		Results []batch.Result `json:"results"`
+	}{summary, results}, "", "  ")
+	if err != nil {
+		fatalf("Error encoding results: %v", err)
+	}
+	if err := os.WriteFile(resultsPath, resultsJSON, 0o644); err != nil {
+		fatalf("Error writing results file: %v", err)
+	}
+
+	fmt.Printf("\nDone in %s: %d succeeded, %d failed, %d cache hit(s)\n",
+		summary.WallTime.Round(time.Millisecond), summary.Succeeded, summary.Failed, summary.CacheHits)
+	fmt.Printf("Results written to %s\n", resultsPath)
+	if summary.Failed > 0 {
+		os.Exit(1)
+	}
+}
+
+// generator holds everything a single item's generation needs, shared
+// across single-item and batch runs.
+type generator struct {
+	backend     imagegen.Backend
+	backendName string
+	cfg         imagegen.Config
+	cache       *cache.Cache
+	sink        outputsink.Sink
+	timeout     time.Duration
+
+	responseFormat string
+	quality        string
+	style          string
+}
+
+// generate runs one manifest item end to end: load images, check the
+// cache, call the backend, write the output file, and populate the cache.
+// It implements batch.ProcessFunc so it can be passed straight to batch.Run.
+// g.timeout bounds the whole call (including async job polling), whether
+// generate is invoked directly (single-item mode) or via a batch worker.
+func (g *generator) generate(ctx context.Context, item batch.Item) (outputFile string, cacheHit bool, err error) {
+	ctx, cancel := context.WithTimeout(ctx, g.timeout)
+	defer cancel()
+
+	outputFile = item.Output
+	if outputFile == "" {
+		if item.Input != "" {
+			outputFile = deriveOutputPath(item.Input)
+		} else {
+			outputFile = "generated.png"
+		}
+	}
+
+	var images []imagegen.ImageInput
+	if item.Background != "" {
+		if item.Input == "" {
+			return "", false, fmt.Errorf("background requires an input image to composite onto")
+		}
+		img, err := loadImage(item.Background)
+		if err != nil {
+			return "", false, fmt.Errorf("reading background image: %w", err)
+		}
+		images = append(images, img)
+	}
+	if item.Input != "" {
+		img, err := loadImage(item.Input)
+		if err != nil {
+			return "", false, fmt.Errorf("reading foreground image: %w", err)
+		}
+		images = append(images, img)
+	}
+
+	var mask *imagegen.ImageInput
+	if item.Mask != "" {
+		if item.Input == "" {
+			return "", false, fmt.Errorf("mask requires an input image to inpaint")
+		}
+		if err := validateMaskDimensions(item.Mask, item.Input); err != nil {
+			return "", false, err
+		}
+		img, err := loadImage(item.Mask)
+		if err != nil {
+			return "", false, fmt.Errorf("reading mask image: %w", err)
+		}
+		mask = &img
+	}
+
+	var cacheKey string
+	outputExt := filepath.Ext(outputFile)
+	if g.cache != nil {
+		keyParts := []string{item.Prompt, item.Size, g.backendName, deploymentLabel(g.backendName, g.cfg),
+			g.responseFormat, g.quality, g.style}
+		keyParts = append(keyParts, hashImages(images)...)
+		if mask != nil {
+			keyParts = append(keyParts, cache.HashBytes(mask.Data))
+		}
+		cacheKey = cache.Key(keyParts...)
+		if data, ok, err := g.cache.Get(cacheKey, outputExt); err != nil {
+			return "", false, fmt.Errorf("reading generation cache: %w", err)
+		} else if ok {
+			dest, err := g.sink.Put(ctx, outputFile, data, outputMetadata(item, outputExt))
+			if err != nil {
+				return "", false, fmt.Errorf("writing output: %w", err)
+			}
+			return dest, true, nil
+		}
+	}
+
+	req := imagegen.GenerateRequest{
+		Prompt:         item.Prompt,
+		Images:         images,
+		Mask:           mask,
+		Size:           item.Size,
+		N:              1,
+		ResponseFormat: g.responseFormat,
+		Quality:        g.quality,
+		Style:          g.style,
+		OnProgress:     printProgress,
+	}
+
+	var resp imagegen.GenerateResponse
+	var genErr error
+	if item.Input != "" {
+		resp, genErr = g.backend.Edit(ctx, req)
+	} else {
+		resp, genErr = g.backend.Generate(ctx, req)
+	}
+	if genErr != nil {
+		return "", false, genErr
+	}
+	if len(resp.Images) == 0 || (resp.Images[0].B64JSON == "" && resp.Images[0].URL == "") {
+		return "", false, fmt.Errorf("backend returned no usable image data")
+	}
+
+	// response_format=url backends return a hosted URL instead of bytes;
+	// there's nothing to write to disk or cache.
+	if resp.Images[0].B64JSON == "" {
+		return resp.Images[0].URL, false, nil
+	}
+
+	imageBytes, err := base64.StdEncoding.DecodeString(resp.Images[0].B64JSON)
+	if err != nil {
+		return "", false, fmt.Errorf("decoding base64 image: %w", err)
+	}
+
+	dest, err := g.sink.Put(ctx, outputFile, imageBytes, outputMetadata(item, outputExt))
+	if err != nil {
+		return "", false, fmt.Errorf("writing output: %w", err)
+	}
+
+	if g.cache != nil {
+		if err := g.cache.Put(cacheKey, outputExt, imageBytes); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to write generation cache: %v\n", err)
+		}
+	}
+
+	return dest, false, nil
+}
+
+// outputMetadata builds the Metadata passed to an outputsink.Sink, so
+// sinks that record it (e.g. as object headers) have the prompt and
+// content type available.
+func outputMetadata(item batch.Item, outputExt string) outputsink.Metadata {
+	contentType := mime.TypeByExtension(outputExt)
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	var sources []string
+	for _, s := range []string{item.Input, item.Background, item.Mask} {
+		if s != "" {
+			sources = append(sources, s)
+		}
+	}
+	return outputsink.Metadata{ContentType: contentType, Prompt: item.Prompt, SourceImages: sources}
+}
+
+// openCache opens the generation cache at dir (or the default location
+// when dir is empty). On any setup error it warns and disables caching
+// rather than failing the run.
+func openCache(dir string) *cache.Cache {
+	if dir == "" {
+		var err error
+		dir, err = cache.DefaultDir()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: disabling cache: %v\n", err)
+			return nil
+		}
+	}
+	c, err := cache.New(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: disabling cache: %v\n", err)
+		return nil
+	}
+	return c
+}
+
+// hashImages returns the sha256 of each image's bytes, for inclusion in a
+// cache key.
+func hashImages(images []imagegen.ImageInput) []string {
+	hashes := make([]string, len(images))
+	for i, img := range images {
+		hashes[i] = cache.HashBytes(img.Data)
+	}
+	return hashes
+}
+
+// deploymentLabel identifies the specific model/endpoint a backend targets,
+// so cache keys don't collide across different deployments of the same
+// backend kind.
+func deploymentLabel(backendName string, cfg imagegen.Config) string {
+	switch backendName {
+	case "", "azure":
+		return cfg.AzureDeployment
+	case "openai":
+		return cfg.OpenAIModel
+	case "stability":
+		return cfg.StabilityEngine
+	case "local":
+		return cfg.LocalBaseURL
+	default:
+		return ""
+	}
+}
+
+// printProgress reports progress for async/job-based backends (e.g. a local
+// Fooocus server). Synchronous backends never call it.
+func printProgress(percent int) {
+	fmt.Printf("  ...job progress: %d%%\n", percent)
+}
+
+// loadImage reads path into an imagegen.ImageInput, tagging it with the
+// MIME type inferred from its extension.
+func loadImage(path string) (imagegen.ImageInput, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return imagegen.ImageInput{}, fmt.Errorf("opening image %s: %w", path, err)
+	}
+	mimeType := mime.TypeByExtension(filepath.Ext(path))
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	return imagegen.ImageInput{Name: filepath.Base(path), MimeType: mimeType, Data: data}, nil
+}
+
+// validateMaskDimensions fails early with a clear error if maskPath and
+// fgPath don't have matching dimensions, since the /images/edits contract
+// requires the mask to align pixel-for-pixel with the foreground image.
+func validateMaskDimensions(maskPath, fgPath string) error {
+	maskCfg, err := decodeImageConfig(maskPath)
+	if err != nil {
+		return fmt.Errorf("reading mask image %s: %w", maskPath, err)
+	}
+	fgCfg, err := decodeImageConfig(fgPath)
+	if err != nil {
+		return fmt.Errorf("reading foreground image %s: %w", fgPath, err)
+	}
+	if maskCfg.Width != fgCfg.Width || maskCfg.Height != fgCfg.Height {
+		return fmt.Errorf("mask dimensions (%dx%d) don't match foreground image dimensions (%dx%d)",
+			maskCfg.Width, maskCfg.Height, fgCfg.Width, fgCfg.Height)
+	}
+	return nil
+}
+
+func decodeImageConfig(path string) (image.Config, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return image.Config{}, err
+	}
+	defer f.Close()
+	cfg, _, err := image.DecodeConfig(f)
+	return cfg, err
+}
+
+// deriveOutputPath returns the output file path by appending "_generated"
+// before the file extension.
+// Example: "assets/cat.png" → "assets/cat_generated.png"
+func deriveOutputPath(input string) string {
+	dir := filepath.Dir(input)
+	ext := filepath.Ext(input)
+	name := strings.TrimSuffix(filepath.Base(input), ext)
+	return filepath.Join(dir, name+"_generated"+ext)
+}
+
+// fatalf prints a message to stderr and exits with code 1.
+func fatalf(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, "❌ "+format+"\n", args...)
+	os.Exit(1)
+}
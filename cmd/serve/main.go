@@ -0,0 +1,54 @@
+// Command serve hosts an OpenAI-compatible image API
+// (POST /v1/images/generations, POST /v1/images/edits) backed by this
+// tool's image-generation backend, so existing OpenAI SDKs (Python, JS,
+// LangChain) can point base_url at it and get provider-brokered images
+// without handling that provider's auth themselves.
+//
+// The server itself has no authentication unless -api-key is set: it
+// binds every interface by default and, unguarded, gives anyone who can
+// reach it free use of whatever backend credentials are configured. Set
+// -api-key (or put it behind a reverse proxy / network policy) before
+// running it anywhere less trusted than a local sidecar or LAN.
+//
+// Usage:
+//
+//	go run ./cmd/serve [-addr :8080] [-backend NAME] [-api-key KEY]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+
+	"github.com/joho/godotenv"
+
+	"github.com/CloudNativeLinz/marketing-asset-creation-agent/internal/httpapi"
+	"github.com/CloudNativeLinz/marketing-asset-creation-agent/internal/imagegen"
+)
+
+func main() {
+	if err := godotenv.Load(); err != nil {
+		fmt.Println("No .env file found")
+	}
+
+	addr := flag.String("addr", imagegen.EnvOr("IMAGEGEN_SERVE_ADDR", ":8080"), "Address to listen on")
+	backendName := flag.String("backend", imagegen.EnvOr("IMAGEGEN_BACKEND", "azure"), "Backend to use: azure, openai, stability, or local")
+	apiKey := flag.String("api-key", imagegen.EnvOr("IMAGEGEN_SERVE_API_KEY", ""), "Require this bearer token on every request (default: none, i.e. unauthenticated)")
+	flag.Parse()
+
+	backend, err := imagegen.New(*backendName, imagegen.ConfigFromEnv())
+	if err != nil {
+		log.Fatalf("Error configuring backend: %v", err)
+	}
+
+	srv := httpapi.NewServer(backend, *apiKey)
+
+	if *apiKey == "" {
+		log.Printf("Warning: no -api-key set; the server will accept requests from anyone who can reach %s", *addr)
+	}
+	log.Printf("Serving OpenAI-compatible image API on %s (backend: %s)", *addr, backend.Name())
+	if err := http.ListenAndServe(*addr, srv.Handler()); err != nil {
+		log.Fatalf("Server error: %v", err)
+	}
+}
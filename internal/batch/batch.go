@@ -0,0 +1,193 @@
+// Package batch runs many image-generation requests concurrently from a
+// manifest file, with bounded concurrency, per-item retries on transient
+// errors, and a machine-readable results report.
+package batch
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+)
+
+// Item is a single manifest entry: one image to generate/edit.
+type Item struct {
+	Prompt     string `json:"prompt"`
+	Input      string `json:"input,omitempty"`
+	Background string `json:"background,omitempty"`
+	Mask       string `json:"mask,omitempty"`
+	Size       string `json:"size,omitempty"`
+	Output     string `json:"output,omitempty"`
+}
+
+// LoadManifest reads a JSONL manifest (one Item per line; blank lines are
+// skipped) from path.
+func LoadManifest(path string) ([]Item, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening manifest %s: %w", path, err)
+	}
+	defer f.Close()
+
+	var items []Item
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for lineNo := 1; scanner.Scan(); lineNo++ {
+		line := scanner.Bytes()
+		if len(bytesTrimSpace(line)) == 0 {
+			continue
+		}
+		var item Item
+		if err := json.Unmarshal(line, &item); err != nil {
+			return nil, fmt.Errorf("manifest %s line %d: %w", path, lineNo, err)
+		}
+		if item.Input == "" && item.Output == "" {
+			return nil, fmt.Errorf("manifest %s line %d: text-to-image items (no \"input\") must set \"output\"; "+
+				"otherwise concurrent items would all default to the same file", path, lineNo)
+		}
+		items = append(items, item)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading manifest %s: %w", path, err)
+	}
+	return items, nil
+}
+
+func bytesTrimSpace(b []byte) []byte {
+	start := 0
+	for start < len(b) && (b[start] == ' ' || b[start] == '\t' || b[start] == '\r') {
+		start++
+	}
+	end := len(b)
+	for end > start && (b[end-1] == ' ' || b[end-1] == '\t' || b[end-1] == '\r') {
+		end--
+	}
+	return b[start:end]
+}
+
+// Result is one manifest item's outcome, ready to be serialized to the
+// results file.
+type Result struct {
+	Item     Item          `json:"item"`
+	Output   string        `json:"output,omitempty"`
+	CacheHit bool          `json:"cache_hit,omitempty"`
+	Error    string        `json:"error,omitempty"`
+	Attempts int           `json:"attempts"`
+	Duration time.Duration `json:"-"`
+}
+
+// MarshalJSON reports Duration in milliseconds (field "duration_ms"):
+// time.Duration's zero-value JSON encoding is its raw nanosecond count,
+// which would silently be off by 1e6 from what the field name promises.
+func (r Result) MarshalJSON() ([]byte, error) {
+	type alias Result
+	return json.Marshal(struct {
+		alias
+		DurationMS int64 `json:"duration_ms"`
+	}{alias: alias(r), DurationMS: r.Duration.Milliseconds()})
+}
+
+// ProcessFunc generates/edits a single item, returning the output path and
+// whether it was served from cache.
+type ProcessFunc func(ctx context.Context, item Item) (output string, cacheHit bool, err error)
+
+// Options configures a batch Run.
+type Options struct {
+	Concurrency int
+	MaxRetries  int              // attempts beyond the first; 0 disables retries
+	IsRetryable func(error) bool // nil treats every error as non-retryable
+	InitialWait time.Duration    // backoff before the first retry; doubles each attempt
+}
+
+// Summary totals a batch Run's results.
+type Summary struct {
+	Total     int           `json:"total"`
+	Succeeded int           `json:"succeeded"`
+	Failed    int           `json:"failed"`
+	CacheHits int           `json:"cache_hits"`
+	WallTime  time.Duration `json:"-"`
+}
+
+// MarshalJSON reports WallTime in milliseconds (field "wall_time_ms"); see
+// Result.MarshalJSON for why this can't just be a struct tag.
+func (s Summary) MarshalJSON() ([]byte, error) {
+	type alias Summary
+	return json.Marshal(struct {
+		alias
+		WallTimeMS int64 `json:"wall_time_ms"`
+	}{alias: alias(s), WallTimeMS: s.WallTime.Milliseconds()})
+}
+
+// Run processes items with a bounded worker pool, retrying transient
+// failures with exponential backoff, and returns one Result per item in
+// manifest order.
+func Run(ctx context.Context, items []Item, process ProcessFunc, opts Options) ([]Result, Summary) {
+	concurrency := opts.Concurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	initialWait := opts.InitialWait
+	if initialWait <= 0 {
+		initialWait = time.Second
+	}
+
+	start := time.Now()
+	results := make([]Result, len(items))
+	sem := make(chan struct{}, concurrency)
+	done := make(chan struct{})
+
+	for i := range items {
+		i := i
+		sem <- struct{}{}
+		go func() {
+			defer func() { <-sem; done <- struct{}{} }()
+			results[i] = runWithRetry(ctx, items[i], process, opts, initialWait)
+		}()
+	}
+	for range items {
+		<-done
+	}
+
+	summary := Summary{Total: len(items), WallTime: time.Since(start)}
+	for _, r := range results {
+		if r.Error == "" {
+			summary.Succeeded++
+		} else {
+			summary.Failed++
+		}
+		if r.CacheHit {
+			summary.CacheHits++
+		}
+	}
+	return results, summary
+}
+
+func runWithRetry(ctx context.Context, item Item, process ProcessFunc, opts Options, initialWait time.Duration) Result {
+	itemStart := time.Now()
+	wait := initialWait
+	var lastErr error
+	attempt := 0
+
+	for {
+		attempt++
+		output, cacheHit, err := process(ctx, item)
+		if err == nil {
+			return Result{Item: item, Output: output, CacheHit: cacheHit, Attempts: attempt, Duration: time.Since(itemStart)}
+		}
+		lastErr = err
+
+		retryable := opts.IsRetryable != nil && opts.IsRetryable(err)
+		if !retryable || attempt > opts.MaxRetries {
+			return Result{Item: item, Error: lastErr.Error(), Attempts: attempt, Duration: time.Since(itemStart)}
+		}
+
+		select {
+		case <-ctx.Done():
+			return Result{Item: item, Error: ctx.Err().Error(), Attempts: attempt, Duration: time.Since(itemStart)}
+		case <-time.After(wait):
+			wait *= 2
+		}
+	}
+}
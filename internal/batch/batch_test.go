@@ -0,0 +1,123 @@
+package batch
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestLoadManifestSkipsBlankLines(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.jsonl")
+	content := `{"prompt":"a cat","input":"cat.png"}
+
+{"prompt":"a dog","size":"512x512","output":"dog.png"}
+`
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	items, err := LoadManifest(path)
+	if err != nil {
+		t.Fatalf("LoadManifest: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("got %d items, want 2", len(items))
+	}
+	if items[0].Prompt != "a cat" || items[1].Size != "512x512" {
+		t.Fatalf("unexpected items: %+v", items)
+	}
+}
+
+func TestLoadManifestRequiresOutputForTextToImageItems(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "manifest.jsonl")
+	content := `{"prompt":"a dog","size":"512x512"}` + "\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	if _, err := LoadManifest(path); err == nil {
+		t.Fatal("expected an error for a text-to-image item with no output")
+	}
+}
+
+func TestResultAndSummaryMarshalDurationsAsMilliseconds(t *testing.T) {
+	result := Result{Duration: 2500 * time.Millisecond}
+	data, err := json.Marshal(result)
+	if err != nil {
+		t.Fatalf("Marshal(Result): %v", err)
+	}
+	var decoded map[string]any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if decoded["duration_ms"] != float64(2500) {
+		t.Fatalf("got duration_ms=%v, want 2500", decoded["duration_ms"])
+	}
+
+	summary := Summary{WallTime: 2500 * time.Millisecond}
+	data, err = json.Marshal(summary)
+	if err != nil {
+		t.Fatalf("Marshal(Summary): %v", err)
+	}
+	decoded = nil
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if decoded["wall_time_ms"] != float64(2500) {
+		t.Fatalf("got wall_time_ms=%v, want 2500", decoded["wall_time_ms"])
+	}
+}
+
+func TestRunRetriesRetryableErrorsThenSucceeds(t *testing.T) {
+	var calls int32
+	process := func(ctx context.Context, item Item) (string, bool, error) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			return "", false, errors.New("transient")
+		}
+		return "out.png", false, nil
+	}
+
+	results, summary := Run(context.Background(), []Item{{Prompt: "x"}}, process, Options{
+		Concurrency: 1,
+		MaxRetries:  5,
+		IsRetryable: func(error) bool { return true },
+		InitialWait: time.Millisecond,
+	})
+
+	if summary.Succeeded != 1 || summary.Failed != 0 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	if results[0].Attempts != 3 || results[0].Output != "out.png" {
+		t.Fatalf("unexpected result: %+v", results[0])
+	}
+}
+
+func TestRunDoesNotRetryNonRetryableErrors(t *testing.T) {
+	var calls int32
+	process := func(ctx context.Context, item Item) (string, bool, error) {
+		atomic.AddInt32(&calls, 1)
+		return "", false, errors.New("permanent")
+	}
+
+	results, summary := Run(context.Background(), []Item{{Prompt: "x"}}, process, Options{
+		Concurrency: 1,
+		MaxRetries:  5,
+		IsRetryable: func(error) bool { return false },
+		InitialWait: time.Millisecond,
+	})
+
+	if summary.Failed != 1 {
+		t.Fatalf("unexpected summary: %+v", summary)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Fatalf("process called %d times, want 1", got)
+	}
+	if results[0].Error == "" {
+		t.Fatalf("expected result to carry the error message")
+	}
+}
@@ -0,0 +1,84 @@
+// Package cache provides a content-addressed on-disk cache for generated
+// images, keyed on the inputs that determine their output (prompt, size,
+// backend, deployment, and the image bytes themselves) so repeated runs
+// with identical inputs skip the round-trip to the backend entirely.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// DefaultDirName is the directory created under the user's cache home
+// (see os.UserCacheDir) when no -cache-dir override is given.
+const DefaultDirName = "marketing-asset-agent"
+
+// Cache stores generated images on disk, one file per cache key.
+type Cache struct {
+	dir string
+}
+
+// New returns a Cache rooted at dir, creating it if necessary.
+func New(dir string) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating cache dir %s: %w", dir, err)
+	}
+	return &Cache{dir: dir}, nil
+}
+
+// DefaultDir returns ~/.cache/marketing-asset-agent (or the platform
+// equivalent via os.UserCacheDir).
+func DefaultDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("resolving user cache dir: %w", err)
+	}
+	return filepath.Join(base, DefaultDirName), nil
+}
+
+// Key hashes parts (prompt, size, backend, deployment, and the sha256 of
+// each input image) into a stable cache key.
+func Key(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0}) // separator so ("ab","c") != ("a","bc")
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// HashBytes returns the hex-encoded sha256 of data, for building Key parts
+// out of image contents.
+func HashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// path returns the on-disk path for a cache key, using ext (including the
+// leading dot) as the file extension.
+func (c *Cache) path(key, ext string) string {
+	return filepath.Join(c.dir, key+ext)
+}
+
+// Get returns the cached bytes for key and ext, or ok=false on a miss.
+func (c *Cache) Get(key, ext string) (data []byte, ok bool, err error) {
+	data, err = os.ReadFile(c.path(key, ext))
+	if os.IsNotExist(err) {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("reading cache entry %s: %w", key, err)
+	}
+	return data, true, nil
+}
+
+// Put stores data under key and ext, overwriting any existing entry.
+func (c *Cache) Put(key, ext string, data []byte) error {
+	if err := os.WriteFile(c.path(key, ext), data, 0o644); err != nil {
+		return fmt.Errorf("writing cache entry %s: %w", key, err)
+	}
+	return nil
+}
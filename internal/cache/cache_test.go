@@ -0,0 +1,40 @@
+package cache
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestKeyIsStableAndDistinguishesBoundaries(t *testing.T) {
+	if Key("a", "b") != Key("a", "b") {
+		t.Fatal("Key should be deterministic for identical inputs")
+	}
+	if Key("ab", "c") == Key("a", "bc") {
+		t.Fatal("Key should distinguish different part boundaries with the same concatenation")
+	}
+}
+
+func TestPutGetRoundTrip(t *testing.T) {
+	c, err := New(t.TempDir())
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	key := Key("prompt", "1024x1024", "azure")
+	if _, ok, err := c.Get(key, ".png"); err != nil || ok {
+		t.Fatalf("expected cache miss, got ok=%v err=%v", ok, err)
+	}
+
+	want := []byte("fake-png-bytes")
+	if err := c.Put(key, ".png", want); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	got, ok, err := c.Get(key, ".png")
+	if err != nil || !ok {
+		t.Fatalf("expected cache hit, got ok=%v err=%v", ok, err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("Get returned %q, want %q", got, want)
+	}
+}
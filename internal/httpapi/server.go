@@ -0,0 +1,244 @@
+// Package httpapi hosts an OpenAI-compatible image API
+// (POST /v1/images/generations, POST /v1/images/edits) backed by an
+// imagegen.Backend, so existing OpenAI SDKs can point base_url at this
+// service instead of talking to a provider directly.
+//
+// The server has no authentication of its own beyond the optional API
+// key passed to NewServer: anyone who can reach the listening address
+// gets unmetered use of whatever backend credentials it's configured
+// with. It's meant to run on a trusted network (e.g. a sidecar or a
+// LAN); set an API key, or put it behind a reverse proxy / network
+// policy, before exposing it any more broadly than that.
+package httpapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/CloudNativeLinz/marketing-asset-creation-agent/internal/imagegen"
+)
+
+// maxUploadSize bounds the multipart form this server will parse into
+// memory/temp files for a single /images/edits request.
+const maxUploadSize = 32 << 20 // 32 MiB
+
+// Server hosts the OpenAI-compatible image endpoints on top of a single
+// imagegen.Backend.
+type Server struct {
+	backend imagegen.Backend
+	apiKey  string
+}
+
+// NewServer wraps backend as an HTTP API. When apiKey is non-empty, every
+// request must carry it as a "Bearer" token in its Authorization header
+// (matching how OpenAI SDKs already send their own API key); when empty,
+// the server accepts requests from anyone who can reach it.
+func NewServer(backend imagegen.Backend, apiKey string) *Server {
+	return &Server{backend: backend, apiKey: apiKey}
+}
+
+// Handler returns the http.Handler serving the /v1/images/* routes.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/images/generations", s.handleGenerations)
+	mux.HandleFunc("/v1/images/edits", s.handleEdits)
+	return s.requireAPIKey(mux)
+}
+
+// requireAPIKey rejects requests missing the configured bearer token. It's
+// a no-op when Server was constructed with an empty apiKey.
+func (s *Server) requireAPIKey(next http.Handler) http.Handler {
+	if s.apiKey == "" {
+		return next
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token != s.apiKey {
+			writeError(w, http.StatusUnauthorized, "missing or invalid API key")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// apiResponse is the {data: [{b64_json | url}]} envelope OpenAI returns.
+type apiResponse struct {
+	Data []apiImage `json:"data"`
+}
+
+type apiImage struct {
+	B64JSON string `json:"b64_json,omitempty"`
+	URL     string `json:"url,omitempty"`
+}
+
+// generationsRequest is the JSON body accepted by /v1/images/generations.
+type generationsRequest struct {
+	Prompt         string `json:"prompt"`
+	N              int    `json:"n"`
+	Size           string `json:"size"`
+	ResponseFormat string `json:"response_format"`
+	Quality        string `json:"quality"`
+	Style          string `json:"style"`
+}
+
+func (s *Server) handleGenerations(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	var req generationsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid JSON body: %v", err))
+		return
+	}
+	if req.Prompt == "" {
+		writeError(w, http.StatusBadRequest, "prompt is required")
+		return
+	}
+
+	resp, err := s.backend.Generate(r.Context(), imagegen.GenerateRequest{
+		Prompt:         req.Prompt,
+		N:              orDefault(req.N, 1),
+		Size:           orDefaultStr(req.Size, "1024x1024"),
+		ResponseFormat: req.ResponseFormat,
+		Quality:        req.Quality,
+		Style:          req.Style,
+	})
+	if err != nil {
+		writeBackendError(w, err)
+		return
+	}
+	writeImages(w, resp)
+}
+
+func (s *Server) handleEdits(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	if err := r.ParseMultipartForm(maxUploadSize); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Sprintf("invalid multipart/form-data body: %v", err))
+		return
+	}
+
+	prompt := r.FormValue("prompt")
+	if prompt == "" {
+		writeError(w, http.StatusBadRequest, "prompt is required")
+		return
+	}
+
+	images, err := readImageFields(r.MultipartForm, "image", "image[]")
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if len(images) == 0 {
+		writeError(w, http.StatusBadRequest, "at least one image is required")
+		return
+	}
+
+	var mask *imagegen.ImageInput
+	if maskImages, err := readImageFields(r.MultipartForm, "mask"); err != nil {
+		writeError(w, http.StatusBadRequest, err.Error())
+		return
+	} else if len(maskImages) > 0 {
+		mask = &maskImages[0]
+		if err := imagegen.ValidateMaskDimensions(*mask, images[0]); err != nil {
+			writeError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	resp, err := s.backend.Edit(r.Context(), imagegen.GenerateRequest{
+		Prompt:         prompt,
+		Images:         images,
+		Mask:           mask,
+		N:              orDefault(atoiOr(r.FormValue("n"), 1), 1),
+		Size:           orDefaultStr(r.FormValue("size"), "1024x1024"),
+		ResponseFormat: r.FormValue("response_format"),
+		Quality:        r.FormValue("quality"),
+		Style:          r.FormValue("style"),
+	})
+	if err != nil {
+		writeBackendError(w, err)
+		return
+	}
+	writeImages(w, resp)
+}
+
+// readImageFields collects every file attached under any of fieldNames
+// (OpenAI clients send a single image as "image" and multiple as "image[]").
+func readImageFields(form *multipart.Form, fieldNames ...string) ([]imagegen.ImageInput, error) {
+	var images []imagegen.ImageInput
+	for _, name := range fieldNames {
+		for _, fh := range form.File[name] {
+			f, err := fh.Open()
+			if err != nil {
+				return nil, fmt.Errorf("opening uploaded file %s: %w", fh.Filename, err)
+			}
+			data, err := io.ReadAll(f)
+			f.Close()
+			if err != nil {
+				return nil, fmt.Errorf("reading uploaded file %s: %w", fh.Filename, err)
+			}
+			mimeType := fh.Header.Get("Content-Type")
+			images = append(images, imagegen.ImageInput{Name: fh.Filename, MimeType: mimeType, Data: data})
+		}
+	}
+	return images, nil
+}
+
+func writeImages(w http.ResponseWriter, resp imagegen.GenerateResponse) {
+	out := apiResponse{}
+	for _, img := range resp.Images {
+		out.Data = append(out.Data, apiImage{B64JSON: img.B64JSON, URL: img.URL})
+	}
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(out); err != nil {
+		log.Printf("httpapi: encoding response: %v", err)
+	}
+}
+
+func writeBackendError(w http.ResponseWriter, err error) {
+	writeError(w, http.StatusBadGateway, err.Error())
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]any{
+		"error": map[string]string{"message": message},
+	})
+}
+
+func orDefault(v, def int) int {
+	if v == 0 {
+		return def
+	}
+	return v
+}
+
+func orDefaultStr(v, def string) string {
+	if v == "" {
+		return def
+	}
+	return v
+}
+
+func atoiOr(s string, def int) int {
+	if s == "" {
+		return def
+	}
+	var n int
+	if _, err := fmt.Sscanf(s, "%d", &n); err != nil {
+		return def
+	}
+	return n
+}
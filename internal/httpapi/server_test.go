@@ -0,0 +1,238 @@
+package httpapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/CloudNativeLinz/marketing-asset-creation-agent/internal/imagegen"
+)
+
+// fakeBackend is a stub imagegen.Backend for exercising the HTTP layer
+// without a real provider.
+type fakeBackend struct {
+	generateReq *imagegen.GenerateRequest
+	editReq     *imagegen.GenerateRequest
+	resp        imagegen.GenerateResponse
+	err         error
+}
+
+func (b *fakeBackend) Name() string { return "fake" }
+
+func (b *fakeBackend) Generate(_ context.Context, req imagegen.GenerateRequest) (imagegen.GenerateResponse, error) {
+	b.generateReq = &req
+	return b.resp, b.err
+}
+
+func (b *fakeBackend) Edit(_ context.Context, req imagegen.GenerateRequest) (imagegen.GenerateResponse, error) {
+	b.editReq = &req
+	return b.resp, b.err
+}
+
+func TestHandleGenerationsRejectsWrongMethod(t *testing.T) {
+	srv := NewServer(&fakeBackend{}, "")
+	req := httptest.NewRequest(http.MethodGet, "/v1/images/generations", nil)
+	rec := httptest.NewRecorder()
+
+	srv.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestHandleGenerationsRequiresPrompt(t *testing.T) {
+	srv := NewServer(&fakeBackend{}, "")
+	body := strings.NewReader(`{"size":"1024x1024"}`)
+	req := httptest.NewRequest(http.MethodPost, "/v1/images/generations", body)
+	rec := httptest.NewRecorder()
+
+	srv.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHandleGenerationsRejectsMalformedJSON(t *testing.T) {
+	srv := NewServer(&fakeBackend{}, "")
+	req := httptest.NewRequest(http.MethodPost, "/v1/images/generations", strings.NewReader("{not json"))
+	rec := httptest.NewRecorder()
+
+	srv.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHandleGenerationsReturnsBackendImages(t *testing.T) {
+	backend := &fakeBackend{resp: imagegen.GenerateResponse{Images: []imagegen.GeneratedImage{{B64JSON: "abc123"}}}}
+	srv := NewServer(backend, "")
+	body := strings.NewReader(`{"prompt":"a cat"}`)
+	req := httptest.NewRequest(http.MethodPost, "/v1/images/generations", body)
+	rec := httptest.NewRecorder()
+
+	srv.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusOK)
+	}
+	var decoded apiResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &decoded); err != nil {
+		t.Fatalf("decoding response: %v", err)
+	}
+	if len(decoded.Data) != 1 || decoded.Data[0].B64JSON != "abc123" {
+		t.Fatalf("unexpected response body: %+v", decoded)
+	}
+	if backend.generateReq == nil || backend.generateReq.Prompt != "a cat" {
+		t.Fatalf("backend.Generate not called with expected prompt: %+v", backend.generateReq)
+	}
+}
+
+func TestHandlerRejectsRequestsMissingAPIKey(t *testing.T) {
+	srv := NewServer(&fakeBackend{}, "secret")
+	req := httptest.NewRequest(http.MethodPost, "/v1/images/generations", strings.NewReader(`{"prompt":"a cat"}`))
+	rec := httptest.NewRecorder()
+
+	srv.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestHandlerAcceptsRequestsWithValidAPIKey(t *testing.T) {
+	backend := &fakeBackend{resp: imagegen.GenerateResponse{Images: []imagegen.GeneratedImage{{B64JSON: "abc123"}}}}
+	srv := NewServer(backend, "secret")
+	req := httptest.NewRequest(http.MethodPost, "/v1/images/generations", strings.NewReader(`{"prompt":"a cat"}`))
+	req.Header.Set("Authorization", "Bearer secret")
+	rec := httptest.NewRecorder()
+
+	srv.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestHandleEditsRejectsWrongMethod(t *testing.T) {
+	srv := NewServer(&fakeBackend{}, "")
+	req := httptest.NewRequest(http.MethodGet, "/v1/images/edits", nil)
+	rec := httptest.NewRecorder()
+
+	srv.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusMethodNotAllowed)
+	}
+}
+
+func TestHandleEditsRequiresImage(t *testing.T) {
+	srv := NewServer(&fakeBackend{}, "")
+	body, contentType := multipartBody(t, map[string]string{"prompt": "a cat"}, nil)
+	req := httptest.NewRequest(http.MethodPost, "/v1/images/edits", body)
+	req.Header.Set("Content-Type", contentType)
+	rec := httptest.NewRecorder()
+
+	srv.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHandleEditsWiresImageAndMaskToBackend(t *testing.T) {
+	backend := &fakeBackend{resp: imagegen.GenerateResponse{Images: []imagegen.GeneratedImage{{B64JSON: "xyz"}}}}
+	srv := NewServer(backend, "")
+	body, contentType := multipartBody(t, map[string]string{"prompt": "a cat"}, map[string][]byte{
+		"image": encodePNG(t, 4, 4),
+		"mask":  encodePNG(t, 4, 4),
+	})
+	req := httptest.NewRequest(http.MethodPost, "/v1/images/edits", body)
+	req.Header.Set("Content-Type", contentType)
+	rec := httptest.NewRecorder()
+
+	srv.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusOK)
+	}
+	if backend.editReq == nil {
+		t.Fatal("backend.Edit was not called")
+	}
+	if len(backend.editReq.Images) != 1 {
+		t.Fatalf("got %d images, want 1", len(backend.editReq.Images))
+	}
+	if backend.editReq.Mask == nil {
+		t.Fatal("expected the mask field to be wired through")
+	}
+}
+
+func TestHandleEditsRejectsMismatchedMaskDimensions(t *testing.T) {
+	backend := &fakeBackend{resp: imagegen.GenerateResponse{Images: []imagegen.GeneratedImage{{B64JSON: "xyz"}}}}
+	srv := NewServer(backend, "")
+	body, contentType := multipartBody(t, map[string]string{"prompt": "a cat"}, map[string][]byte{
+		"image": encodePNG(t, 4, 4),
+		"mask":  encodePNG(t, 8, 8),
+	})
+	req := httptest.NewRequest(http.MethodPost, "/v1/images/edits", body)
+	req.Header.Set("Content-Type", contentType)
+	rec := httptest.NewRecorder()
+
+	srv.Handler().ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("got status %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if backend.editReq != nil {
+		t.Fatal("backend.Edit should not have been called with a mismatched mask")
+	}
+}
+
+// encodePNG returns a minimal width x height PNG, for tests that need
+// bytes image.DecodeConfig can actually parse.
+func encodePNG(t *testing.T, width, height int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	img.Set(0, 0, color.White)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatalf("encoding PNG: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// multipartBody builds a multipart/form-data request body with the given
+// text fields and (field name -> bytes) file parts.
+func multipartBody(t *testing.T, fields map[string]string, files map[string][]byte) (*bytes.Buffer, string) {
+	t.Helper()
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	for k, v := range fields {
+		if err := w.WriteField(k, v); err != nil {
+			t.Fatalf("WriteField(%s): %v", k, err)
+		}
+	}
+	for name, data := range files {
+		fw, err := w.CreateFormFile(name, name+".png")
+		if err != nil {
+			t.Fatalf("CreateFormFile(%s): %v", name, err)
+		}
+		if _, err := fw.Write(data); err != nil {
+			t.Fatalf("writing file part %s: %v", name, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("closing multipart writer: %v", err)
+	}
+	return &buf, w.FormDataContentType()
+}
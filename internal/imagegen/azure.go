@@ -0,0 +1,152 @@
+package imagegen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+)
+
+// AzureBackend talks to an Azure OpenAI image deployment using Azure AD
+// bearer tokens (DefaultAzureCredential), the original behavior of this
+// tool before it supported multiple backends.
+type AzureBackend struct {
+	resourceHost string
+	deployment   string
+	apiVersion   string
+	client       *http.Client
+}
+
+// NewAzureBackend constructs an AzureBackend from cfg, validating that the
+// required resource/deployment/api-version settings are present.
+func NewAzureBackend(cfg Config) (*AzureBackend, error) {
+	if cfg.AzureResourceHost == "" || cfg.AzureDeployment == "" || cfg.AzureAPIVersion == "" {
+		return nil, fmt.Errorf("azure backend requires AZURE_OPENAI_RESOURCE, AZURE_OPENAI_DEPLOYMENT, and AZURE_OPENAI_API_VERSION")
+	}
+	return &AzureBackend{
+		resourceHost: cfg.AzureResourceHost,
+		deployment:   cfg.AzureDeployment,
+		apiVersion:   cfg.AzureAPIVersion,
+		client:       &http.Client{Timeout: 5 * time.Minute},
+	}, nil
+}
+
+func (b *AzureBackend) Name() string { return "azure" }
+
+// Generate calls Azure's /images/generations endpoint (no input image).
+func (b *AzureBackend) Generate(ctx context.Context, req GenerateRequest) (GenerateResponse, error) {
+	return b.call(ctx, "generations", req)
+}
+
+// Edit calls Azure's /images/edits endpoint, sending the foreground
+// (and optional background/mask) images alongside the prompt.
+func (b *AzureBackend) Edit(ctx context.Context, req GenerateRequest) (GenerateResponse, error) {
+	return b.call(ctx, "edits", req)
+}
+
+func (b *AzureBackend) call(ctx context.Context, op string, req GenerateRequest) (GenerateResponse, error) {
+	token, err := getAzureToken(ctx)
+	if err != nil {
+		return GenerateResponse{}, fmt.Errorf("obtaining Azure access token: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("https://%s/openai/deployments/%s/images/%s?api-version=%s",
+		b.resourceHost, b.deployment, op, b.apiVersion)
+
+	n := req.N
+	if n == 0 {
+		n = 1
+	}
+	fields := map[string]string{
+		"prompt":          req.Prompt,
+		"n":               fmt.Sprintf("%d", n),
+		"size":            req.Size,
+		"response_format": req.ResponseFormat,
+		"quality":         req.Quality,
+		"style":           req.Style,
+	}
+
+	body, contentType, err := buildMultipartBody(req.Images, req.Mask, fields)
+	if err != nil {
+		return GenerateResponse{}, fmt.Errorf("building request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return GenerateResponse{}, fmt.Errorf("creating HTTP request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+token)
+	httpReq.Header.Set("Content-Type", contentType)
+
+	resp, err := b.client.Do(httpReq)
+	if err != nil {
+		return GenerateResponse{}, fmt.Errorf("sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return GenerateResponse{}, fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return GenerateResponse{}, &HTTPError{StatusCode: resp.StatusCode, Message: truncate(string(respBytes), 500)}
+	}
+
+	var result struct {
+		Data []struct {
+			B64JSON string `json:"b64_json"`
+			URL     string `json:"url"`
+		} `json:"data"`
+		Error *struct {
+			Message string `json:"message"`
+			Type    string `json:"type"`
+			Code    string `json:"code"`
+		} `json:"error,omitempty"`
+	}
+	if err := json.Unmarshal(respBytes, &result); err != nil {
+		return GenerateResponse{}, fmt.Errorf("parsing response JSON: %w (raw: %s)", err, truncate(string(respBytes), 500))
+	}
+	if result.Error != nil {
+		return GenerateResponse{}, fmt.Errorf("image %s failed: %s (type=%s, code=%s)", op, result.Error.Message, result.Error.Type, result.Error.Code)
+	}
+	if len(result.Data) == 0 {
+		return GenerateResponse{}, fmt.Errorf("image %s failed: no image data in response", op)
+	}
+
+	out := GenerateResponse{}
+	for _, d := range result.Data {
+		out.Images = append(out.Images, GeneratedImage{B64JSON: d.B64JSON, URL: d.URL})
+	}
+	return out, nil
+}
+
+// getAzureToken obtains a bearer token for the Cognitive Services resource
+// using DefaultAzureCredential, which automatically tries multiple
+// credential types (environment variables, managed identity, Azure CLI
+// token cache, etc.) without requiring the az CLI binary at runtime.
+func getAzureToken(ctx context.Context) (string, error) {
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return "", fmt.Errorf("creating credential: %w", err)
+	}
+	tk, err := cred.GetToken(ctx, policy.TokenRequestOptions{
+		Scopes: []string{"https://cognitiveservices.azure.com/.default"},
+	})
+	if err != nil {
+		return "", fmt.Errorf("acquiring token: %w", err)
+	}
+	return tk.Token, nil
+}
+
+// truncate returns at most n bytes of s.
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
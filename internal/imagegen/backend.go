@@ -0,0 +1,98 @@
+// Package imagegen defines a pluggable interface for image generation/edit
+// backends (Azure OpenAI, vanilla OpenAI, Stability AI, and local
+// Stable Diffusion / Fooocus-compatible servers) so the CLI can target
+// whichever service is reachable without changing call sites.
+package imagegen
+
+import (
+	"context"
+	"fmt"
+)
+
+// ImageInput is a single image attached to a request, e.g. a foreground,
+// background, or mask file.
+type ImageInput struct {
+	Name     string
+	MimeType string
+	Data     []byte
+}
+
+// GenerateRequest describes an image generation or edit call. Backends that
+// don't support a given field (e.g. Style on a non-OpenAI backend) ignore it.
+type GenerateRequest struct {
+	Prompt         string
+	Images         []ImageInput // foreground/background inputs, in order
+	Mask           *ImageInput
+	Size           string
+	N              int
+	ResponseFormat string // "b64_json" or "url"
+	Quality        string
+	Style          string
+
+	// OnProgress, if set, is called as an async job advances. Backends
+	// that complete synchronously (Azure, OpenAI, Stability) never call it.
+	OnProgress func(percent int)
+}
+
+// GenerateResponse holds the images produced by a backend. Exactly one of
+// B64 or URL is set per returned image, depending on ResponseFormat.
+type GenerateResponse struct {
+	Images []GeneratedImage
+}
+
+// GeneratedImage is a single image result.
+type GeneratedImage struct {
+	B64JSON string
+	URL     string
+}
+
+// Backend is implemented by each image-generation provider this tool can
+// talk to.
+type Backend interface {
+	// Name identifies the backend, e.g. "azure", "openai", "stability", "local".
+	Name() string
+
+	// Generate creates an image from a text prompt alone (no input image).
+	Generate(ctx context.Context, req GenerateRequest) (GenerateResponse, error)
+
+	// Edit creates an image from a prompt plus one or more input images
+	// (and, optionally, a mask for inpainting).
+	Edit(ctx context.Context, req GenerateRequest) (GenerateResponse, error)
+}
+
+// Config carries the settings needed to construct any backend. Each backend
+// only reads the fields relevant to it.
+type Config struct {
+	// Azure OpenAI
+	AzureResourceHost string
+	AzureDeployment   string
+	AzureAPIVersion   string
+
+	// Vanilla OpenAI
+	OpenAIAPIKey string
+	OpenAIModel  string
+
+	// Stability AI
+	StabilityAPIKey string
+	StabilityEngine string
+
+	// Local Stable Diffusion / Fooocus-compatible server
+	LocalBaseURL string
+}
+
+// New constructs the Backend named by backend ("azure", "openai",
+// "stability", or "local") using cfg for its credentials/endpoint.
+func New(backend string, cfg Config) (Backend, error) {
+	switch backend {
+	case "", "azure":
+		return NewAzureBackend(cfg)
+	case "openai":
+		return NewOpenAIBackend(cfg)
+	case "stability":
+		return NewStabilityBackend(cfg)
+	case "local":
+		return NewLocalBackend(cfg)
+	default:
+		return nil, fmt.Errorf("unknown backend %q (want azure, openai, stability, or local)", backend)
+	}
+}
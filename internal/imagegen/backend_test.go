@@ -0,0 +1,47 @@
+package imagegen
+
+import "testing"
+
+func TestNewDispatchesToBackendConstructor(t *testing.T) {
+	cases := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{"", Config{AzureResourceHost: "h", AzureDeployment: "d", AzureAPIVersion: "v"}, "azure"},
+		{"azure", Config{AzureResourceHost: "h", AzureDeployment: "d", AzureAPIVersion: "v"}, "azure"},
+		{"openai", Config{OpenAIAPIKey: "key"}, "openai"},
+		{"stability", Config{StabilityAPIKey: "key"}, "stability"},
+		{"local", Config{LocalBaseURL: "http://localhost:8080"}, "local"},
+	}
+	for _, c := range cases {
+		backend, err := New(c.name, c.cfg)
+		if err != nil {
+			t.Fatalf("New(%q): %v", c.name, err)
+		}
+		if got := backend.Name(); got != c.want {
+			t.Errorf("New(%q).Name() = %q, want %q", c.name, got, c.want)
+		}
+	}
+}
+
+func TestNewUnknownBackendErrors(t *testing.T) {
+	if _, err := New("bogus", Config{}); err == nil {
+		t.Fatal("expected an error for an unknown backend name")
+	}
+}
+
+func TestBackendConstructorsValidateRequiredConfig(t *testing.T) {
+	if _, err := NewAzureBackend(Config{}); err == nil {
+		t.Error("NewAzureBackend: expected an error with no Azure config")
+	}
+	if _, err := NewOpenAIBackend(Config{}); err == nil {
+		t.Error("NewOpenAIBackend: expected an error with no OPENAI_API_KEY")
+	}
+	if _, err := NewStabilityBackend(Config{}); err == nil {
+		t.Error("NewStabilityBackend: expected an error with no STABILITY_API_KEY")
+	}
+	if _, err := NewLocalBackend(Config{}); err == nil {
+		t.Error("NewLocalBackend: expected an error with no LOCAL_IMAGEGEN_URL")
+	}
+}
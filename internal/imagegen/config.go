@@ -0,0 +1,30 @@
+package imagegen
+
+import "os"
+
+// ConfigFromEnv reads backend credentials/endpoints from the environment.
+// It doesn't fail on missing values: each Backend constructor validates
+// only the fields the selected backend actually needs.
+func ConfigFromEnv() Config {
+	return Config{
+		AzureResourceHost: os.Getenv("AZURE_OPENAI_RESOURCE"),
+		AzureDeployment:   os.Getenv("AZURE_OPENAI_DEPLOYMENT"),
+		AzureAPIVersion:   os.Getenv("AZURE_OPENAI_API_VERSION"),
+
+		OpenAIAPIKey: os.Getenv("OPENAI_API_KEY"),
+		OpenAIModel:  os.Getenv("OPENAI_MODEL"),
+
+		StabilityAPIKey: os.Getenv("STABILITY_API_KEY"),
+		StabilityEngine: os.Getenv("STABILITY_ENGINE"),
+
+		LocalBaseURL: os.Getenv("LOCAL_IMAGEGEN_URL"),
+	}
+}
+
+// EnvOr returns the value of environment variable key, or def if unset.
+func EnvOr(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+	return def
+}
@@ -0,0 +1,32 @@
+package imagegen
+
+import (
+	"errors"
+	"fmt"
+)
+
+// HTTPError wraps a non-2xx HTTP response from a backend so callers (e.g.
+// batch mode) can decide whether it's worth retrying.
+type HTTPError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("http %d: %s", e.StatusCode, e.Message)
+}
+
+// Retryable reports whether the failure is transient (rate limited or a
+// server error) and worth retrying with backoff.
+func (e *HTTPError) Retryable() bool {
+	return e.StatusCode == 429 || e.StatusCode >= 500
+}
+
+// IsRetryable reports whether err is an HTTPError worth retrying.
+func IsRetryable(err error) bool {
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		return false
+	}
+	return httpErr.Retryable()
+}
@@ -0,0 +1,163 @@
+package imagegen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// LocalBackend talks to a self-hosted Stable Diffusion / Fooocus-compatible
+// HTTP server that exposes the same /v1/images/generations and
+// /v1/images/edits shape as OpenAI. No authentication is required by
+// default, matching how these servers are typically run on a trusted LAN.
+type LocalBackend struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewLocalBackend constructs a LocalBackend from cfg.
+func NewLocalBackend(cfg Config) (*LocalBackend, error) {
+	if cfg.LocalBaseURL == "" {
+		return nil, fmt.Errorf("local backend requires LOCAL_IMAGEGEN_URL (e.g. http://localhost:8080)")
+	}
+	return &LocalBackend{
+		baseURL: strings.TrimSuffix(cfg.LocalBaseURL, "/"),
+		client:  &http.Client{Timeout: 5 * time.Minute},
+	}, nil
+}
+
+func (b *LocalBackend) Name() string { return "local" }
+
+func (b *LocalBackend) Generate(ctx context.Context, req GenerateRequest) (GenerateResponse, error) {
+	return b.call(ctx, "generations", req)
+}
+
+func (b *LocalBackend) Edit(ctx context.Context, req GenerateRequest) (GenerateResponse, error) {
+	return b.call(ctx, "edits", req)
+}
+
+func (b *LocalBackend) call(ctx context.Context, op string, req GenerateRequest) (GenerateResponse, error) {
+	endpoint := b.baseURL + "/v1/images/" + op
+
+	n := req.N
+	if n == 0 {
+		n = 1
+	}
+	fields := map[string]string{
+		"prompt":          req.Prompt,
+		"n":               fmt.Sprintf("%d", n),
+		"size":            req.Size,
+		"response_format": req.ResponseFormat,
+	}
+
+	body, contentType, err := buildMultipartBody(req.Images, req.Mask, fields)
+	if err != nil {
+		return GenerateResponse{}, fmt.Errorf("building request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return GenerateResponse{}, fmt.Errorf("creating HTTP request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", contentType)
+
+	resp, err := b.client.Do(httpReq)
+	if err != nil {
+		return GenerateResponse{}, fmt.Errorf("sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return GenerateResponse{}, fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return GenerateResponse{}, &HTTPError{StatusCode: resp.StatusCode, Message: truncate(string(respBytes), 500)}
+	}
+
+	var result localResult
+	if err := json.Unmarshal(respBytes, &result); err != nil {
+		return GenerateResponse{}, fmt.Errorf("parsing response JSON: %w (raw: %s)", err, truncate(string(respBytes), 500))
+	}
+	if result.Error != nil {
+		return GenerateResponse{}, fmt.Errorf("local %s failed: %s", op, result.Error.Message)
+	}
+
+	// Fooocus/Runpod-style backends accept the job and return an id instead
+	// of image data; poll the status endpoint until it completes.
+	if result.JobID != "" && len(result.Data) == 0 {
+		if err := b.awaitJob(ctx, result.JobID, req.OnProgress, &result); err != nil {
+			return GenerateResponse{}, err
+		}
+	}
+
+	if len(result.Data) == 0 {
+		return GenerateResponse{}, fmt.Errorf("local %s failed: no image data in response", op)
+	}
+
+	out := GenerateResponse{}
+	for _, d := range result.Data {
+		out.Images = append(out.Images, GeneratedImage{B64JSON: d.B64JSON, URL: d.URL})
+	}
+	return out, nil
+}
+
+// localResult is the JSON shape returned by both the submit and job-status
+// endpoints: either image data (done) or a job id plus status (pending).
+type localResult struct {
+	Data []struct {
+		B64JSON string `json:"b64_json"`
+		URL     string `json:"url"`
+	} `json:"data"`
+	JobID   string `json:"job_id"`
+	Status  string `json:"status"` // "pending", "processing", "completed", "failed"
+	Percent int    `json:"percent"`
+	Error   *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// awaitJob polls GET {baseURL}/v1/images/jobs/{id} on an exponential-backoff
+// schedule until the job completes, fails, or ctx's deadline (set by the
+// caller from -timeout) elapses. On success *result is replaced with the
+// completed job's data.
+func (b *LocalBackend) awaitJob(ctx context.Context, jobID string, onProgress func(int), result *localResult) error {
+	statusURL := fmt.Sprintf("%s/v1/images/jobs/%s", b.baseURL, jobID)
+
+	return pollUntilDone(ctx, onProgress, func(ctx context.Context) (bool, int, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, statusURL, nil)
+		if err != nil {
+			return false, 0, fmt.Errorf("creating job status request: %w", err)
+		}
+		resp, err := b.client.Do(httpReq)
+		if err != nil {
+			return false, 0, fmt.Errorf("checking job status: %w", err)
+		}
+		defer resp.Body.Close()
+
+		respBytes, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return false, 0, fmt.Errorf("reading job status: %w", err)
+		}
+		var status localResult
+		if err := json.Unmarshal(respBytes, &status); err != nil {
+			return false, 0, fmt.Errorf("parsing job status JSON: %w (raw: %s)", err, truncate(string(respBytes), 500))
+		}
+		if status.Status == "failed" {
+			msg := "job failed"
+			if status.Error != nil {
+				msg = status.Error.Message
+			}
+			return false, status.Percent, fmt.Errorf("local job %s failed: %s", jobID, msg)
+		}
+		if status.Status == "completed" || len(status.Data) > 0 {
+			*result = status
+			return true, 100, nil
+		}
+		return false, status.Percent, nil
+	})
+}
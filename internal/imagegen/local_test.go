@@ -0,0 +1,149 @@
+package imagegen
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newLocalBackend(t *testing.T, srv *httptest.Server) *LocalBackend {
+	t.Helper()
+	b, err := NewLocalBackend(Config{LocalBaseURL: srv.URL})
+	if err != nil {
+		t.Fatalf("NewLocalBackend: %v", err)
+	}
+	return b
+}
+
+func TestLocalBackendGenerateParsesSyncSuccess(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/images/generations" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"data": []map[string]string{{"b64_json": "abc123"}},
+		})
+	}))
+	defer srv.Close()
+
+	resp, err := newLocalBackend(t, srv).Generate(context.Background(), GenerateRequest{Prompt: "a cat"})
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if len(resp.Images) != 1 || resp.Images[0].B64JSON != "abc123" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+}
+
+func TestLocalBackendCallReturnsRetryableHTTPErrorOn5xx(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		w.Write([]byte("server overloaded"))
+	}))
+	defer srv.Close()
+
+	_, err := newLocalBackend(t, srv).Generate(context.Background(), GenerateRequest{Prompt: "a cat"})
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) {
+		t.Fatalf("got %v, want an *HTTPError", err)
+	}
+	if httpErr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("got status %d, want %d", httpErr.StatusCode, http.StatusServiceUnavailable)
+	}
+	if !httpErr.Retryable() || !IsRetryable(err) {
+		t.Fatal("expected a 503 to be retryable")
+	}
+}
+
+func TestLocalBackendCallReturnsNonRetryableHTTPErrorOn4xx(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("bad prompt"))
+	}))
+	defer srv.Close()
+
+	_, err := newLocalBackend(t, srv).Generate(context.Background(), GenerateRequest{Prompt: "a cat"})
+	if IsRetryable(err) {
+		t.Fatal("expected a 400 to not be retryable")
+	}
+}
+
+func TestLocalBackendAwaitJobPollsUntilCompleted(t *testing.T) {
+	var pollCount int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/images/generations":
+			json.NewEncoder(w).Encode(map[string]any{"job_id": "job-1", "status": "pending"})
+		case "/v1/images/jobs/job-1":
+			pollCount++
+			if pollCount < 2 {
+				json.NewEncoder(w).Encode(map[string]any{"status": "processing", "percent": pollCount * 25})
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]any{
+				"status": "completed",
+				"data":   []map[string]string{{"b64_json": "done-data"}},
+			})
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	defer srv.Close()
+
+	var progress []int
+	req := GenerateRequest{Prompt: "a cat", OnProgress: func(p int) { progress = append(progress, p) }}
+	resp, err := newLocalBackend(t, srv).Generate(context.Background(), req)
+	if err != nil {
+		t.Fatalf("Generate: %v", err)
+	}
+	if len(resp.Images) != 1 || resp.Images[0].B64JSON != "done-data" {
+		t.Fatalf("unexpected response: %+v", resp)
+	}
+	if len(progress) == 0 {
+		t.Fatal("expected at least one progress callback")
+	}
+}
+
+func TestLocalBackendAwaitJobReturnsErrorOnFailedStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/images/generations":
+			json.NewEncoder(w).Encode(map[string]any{"job_id": "job-1", "status": "pending"})
+		case "/v1/images/jobs/job-1":
+			json.NewEncoder(w).Encode(map[string]any{
+				"status": "failed",
+				"error":  map[string]string{"message": "out of memory"},
+			})
+		}
+	}))
+	defer srv.Close()
+
+	_, err := newLocalBackend(t, srv).Generate(context.Background(), GenerateRequest{Prompt: "a cat"})
+	if err == nil {
+		t.Fatal("expected an error for a failed job")
+	}
+}
+
+func TestLocalBackendAwaitJobTimesOut(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/images/generations":
+			json.NewEncoder(w).Encode(map[string]any{"job_id": "job-1", "status": "pending"})
+		case "/v1/images/jobs/job-1":
+			json.NewEncoder(w).Encode(map[string]any{"status": "processing", "percent": 1})
+		}
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := newLocalBackend(t, srv).Generate(ctx, GenerateRequest{Prompt: "a cat"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want a context.DeadlineExceeded-wrapping error", err)
+	}
+}
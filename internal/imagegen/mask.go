@@ -0,0 +1,30 @@
+package imagegen
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+)
+
+// ValidateMaskDimensions fails with a clear error if mask and foreground
+// don't decode to the same pixel dimensions, since the /images/edits
+// contract requires the mask to align pixel-for-pixel with the image it's
+// inpainting.
+func ValidateMaskDimensions(mask, foreground ImageInput) error {
+	maskCfg, _, err := image.DecodeConfig(bytes.NewReader(mask.Data))
+	if err != nil {
+		return fmt.Errorf("reading mask image %s: %w", mask.Name, err)
+	}
+	fgCfg, _, err := image.DecodeConfig(bytes.NewReader(foreground.Data))
+	if err != nil {
+		return fmt.Errorf("reading foreground image %s: %w", foreground.Name, err)
+	}
+	if maskCfg.Width != fgCfg.Width || maskCfg.Height != fgCfg.Height {
+		return fmt.Errorf("mask dimensions (%dx%d) don't match foreground image dimensions (%dx%d)",
+			maskCfg.Width, maskCfg.Height, fgCfg.Width, fgCfg.Height)
+	}
+	return nil
+}
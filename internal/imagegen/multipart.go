@@ -0,0 +1,71 @@
+package imagegen
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/textproto"
+)
+
+// buildMultipartBody creates the multipart/form-data payload expected by the
+// OpenAI-compatible /images/edits endpoints. Multiple images are sent as
+// repeated "image[]" fields; a single image uses "image" to stay compatible
+// with callers that don't send an array.
+func buildMultipartBody(images []ImageInput, mask *ImageInput, fields map[string]string) (*bytes.Buffer, string, error) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+
+	fieldName := "image"
+	if len(images) > 1 {
+		fieldName = "image[]"
+	}
+
+	for _, img := range images {
+		if err := addImagePart(w, fieldName, img); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if mask != nil {
+		if err := addImagePart(w, "mask", *mask); err != nil {
+			return nil, "", err
+		}
+	}
+
+	for k, v := range fields {
+		if v == "" {
+			continue
+		}
+		if err := w.WriteField(k, v); err != nil {
+			return nil, "", fmt.Errorf("writing field %s: %w", k, err)
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return nil, "", fmt.Errorf("closing multipart writer: %w", err)
+	}
+
+	return &buf, w.FormDataContentType(), nil
+}
+
+// addImagePart adds a single image as a multipart form part with the
+// correct MIME type.
+func addImagePart(w *multipart.Writer, fieldName string, img ImageInput) error {
+	mimeType := img.MimeType
+	if mimeType == "" {
+		mimeType = "application/octet-stream"
+	}
+	partHeader := make(textproto.MIMEHeader)
+	partHeader.Set("Content-Disposition",
+		fmt.Sprintf(`form-data; name="%s"; filename="%s"`, fieldName, img.Name))
+	partHeader.Set("Content-Type", mimeType)
+	part, err := w.CreatePart(partHeader)
+	if err != nil {
+		return fmt.Errorf("creating form file for %s: %w", img.Name, err)
+	}
+	if _, err := io.Copy(part, bytes.NewReader(img.Data)); err != nil {
+		return fmt.Errorf("copying image data for %s: %w", img.Name, err)
+	}
+	return nil
+}
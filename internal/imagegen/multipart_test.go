@@ -0,0 +1,89 @@
+package imagegen
+
+import (
+	"mime"
+	"mime/multipart"
+	"strings"
+	"testing"
+)
+
+func TestBuildMultipartBodySingleImageUsesImageField(t *testing.T) {
+	images := []ImageInput{{Name: "fg.png", MimeType: "image/png", Data: []byte("fg-data")}}
+
+	buf, contentType, err := buildMultipartBody(images, nil, map[string]string{"prompt": "a cat"})
+	if err != nil {
+		t.Fatalf("buildMultipartBody: %v", err)
+	}
+
+	form := parseMultipart(t, buf.Bytes(), contentType)
+	if len(form.File["image"]) != 1 {
+		t.Fatalf("got %d \"image\" parts, want 1", len(form.File["image"]))
+	}
+	if len(form.File["image[]"]) != 0 {
+		t.Fatalf("expected no \"image[]\" parts for a single image")
+	}
+	if got := form.Value["prompt"]; len(got) != 1 || got[0] != "a cat" {
+		t.Fatalf("got prompt field %v, want [\"a cat\"]", got)
+	}
+}
+
+func TestBuildMultipartBodyMultipleImagesUseImageArrayField(t *testing.T) {
+	images := []ImageInput{
+		{Name: "bg.png", MimeType: "image/png", Data: []byte("bg-data")},
+		{Name: "fg.png", MimeType: "image/png", Data: []byte("fg-data")},
+	}
+
+	buf, contentType, err := buildMultipartBody(images, nil, nil)
+	if err != nil {
+		t.Fatalf("buildMultipartBody: %v", err)
+	}
+
+	form := parseMultipart(t, buf.Bytes(), contentType)
+	if len(form.File["image[]"]) != 2 {
+		t.Fatalf("got %d \"image[]\" parts, want 2", len(form.File["image[]"]))
+	}
+}
+
+func TestBuildMultipartBodyIncludesMaskField(t *testing.T) {
+	images := []ImageInput{{Name: "fg.png", MimeType: "image/png", Data: []byte("fg-data")}}
+	mask := &ImageInput{Name: "mask.png", MimeType: "image/png", Data: []byte("mask-data")}
+
+	buf, contentType, err := buildMultipartBody(images, mask, nil)
+	if err != nil {
+		t.Fatalf("buildMultipartBody: %v", err)
+	}
+
+	form := parseMultipart(t, buf.Bytes(), contentType)
+	if len(form.File["mask"]) != 1 {
+		t.Fatalf("got %d \"mask\" parts, want 1", len(form.File["mask"]))
+	}
+}
+
+func TestBuildMultipartBodySkipsEmptyFields(t *testing.T) {
+	buf, contentType, err := buildMultipartBody(nil, nil, map[string]string{"style": "", "quality": "hd"})
+	if err != nil {
+		t.Fatalf("buildMultipartBody: %v", err)
+	}
+
+	form := parseMultipart(t, buf.Bytes(), contentType)
+	if _, ok := form.Value["style"]; ok {
+		t.Error("expected an empty field to be omitted")
+	}
+	if got := form.Value["quality"]; len(got) != 1 || got[0] != "hd" {
+		t.Fatalf("got quality field %v, want [\"hd\"]", got)
+	}
+}
+
+func parseMultipart(t *testing.T, body []byte, contentType string) *multipart.Form {
+	t.Helper()
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		t.Fatalf("parsing content type %q: %v", contentType, err)
+	}
+	reader := multipart.NewReader(strings.NewReader(string(body)), params["boundary"])
+	form, err := reader.ReadForm(1 << 20)
+	if err != nil {
+		t.Fatalf("reading multipart form: %v", err)
+	}
+	return form
+}
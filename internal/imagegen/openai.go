@@ -0,0 +1,115 @@
+package imagegen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// OpenAIBackend talks to vanilla OpenAI's /v1/images/* endpoints using an
+// API-key bearer token.
+type OpenAIBackend struct {
+	apiKey string
+	model  string
+	client *http.Client
+}
+
+// NewOpenAIBackend constructs an OpenAIBackend from cfg.
+func NewOpenAIBackend(cfg Config) (*OpenAIBackend, error) {
+	if cfg.OpenAIAPIKey == "" {
+		return nil, fmt.Errorf("openai backend requires OPENAI_API_KEY")
+	}
+	model := cfg.OpenAIModel
+	if model == "" {
+		model = "gpt-image-1"
+	}
+	return &OpenAIBackend{
+		apiKey: cfg.OpenAIAPIKey,
+		model:  model,
+		client: &http.Client{Timeout: 5 * time.Minute},
+	}, nil
+}
+
+func (b *OpenAIBackend) Name() string { return "openai" }
+
+func (b *OpenAIBackend) Generate(ctx context.Context, req GenerateRequest) (GenerateResponse, error) {
+	return b.call(ctx, "generations", req)
+}
+
+func (b *OpenAIBackend) Edit(ctx context.Context, req GenerateRequest) (GenerateResponse, error) {
+	return b.call(ctx, "edits", req)
+}
+
+func (b *OpenAIBackend) call(ctx context.Context, op string, req GenerateRequest) (GenerateResponse, error) {
+	endpoint := "https://api.openai.com/v1/images/" + op
+
+	n := req.N
+	if n == 0 {
+		n = 1
+	}
+	fields := map[string]string{
+		"model":           b.model,
+		"prompt":          req.Prompt,
+		"n":               fmt.Sprintf("%d", n),
+		"size":            req.Size,
+		"response_format": req.ResponseFormat,
+		"quality":         req.Quality,
+		"style":           req.Style,
+	}
+
+	body, contentType, err := buildMultipartBody(req.Images, req.Mask, fields)
+	if err != nil {
+		return GenerateResponse{}, fmt.Errorf("building request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return GenerateResponse{}, fmt.Errorf("creating HTTP request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+b.apiKey)
+	httpReq.Header.Set("Content-Type", contentType)
+
+	resp, err := b.client.Do(httpReq)
+	if err != nil {
+		return GenerateResponse{}, fmt.Errorf("sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return GenerateResponse{}, fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return GenerateResponse{}, &HTTPError{StatusCode: resp.StatusCode, Message: truncate(string(respBytes), 500)}
+	}
+
+	var result struct {
+		Data []struct {
+			B64JSON string `json:"b64_json"`
+			URL     string `json:"url"`
+		} `json:"data"`
+		Error *struct {
+			Message string `json:"message"`
+			Type    string `json:"type"`
+			Code    string `json:"code"`
+		} `json:"error,omitempty"`
+	}
+	if err := json.Unmarshal(respBytes, &result); err != nil {
+		return GenerateResponse{}, fmt.Errorf("parsing response JSON: %w (raw: %s)", err, truncate(string(respBytes), 500))
+	}
+	if result.Error != nil {
+		return GenerateResponse{}, fmt.Errorf("image %s failed: %s (type=%s, code=%s)", op, result.Error.Message, result.Error.Type, result.Error.Code)
+	}
+	if len(result.Data) == 0 {
+		return GenerateResponse{}, fmt.Errorf("image %s failed: no image data in response", op)
+	}
+
+	out := GenerateResponse{}
+	for _, d := range result.Data {
+		out.Images = append(out.Images, GeneratedImage{B64JSON: d.B64JSON, URL: d.URL})
+	}
+	return out, nil
+}
@@ -0,0 +1,40 @@
+package imagegen
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// pollUntilDone repeatedly calls check on an exponential-backoff schedule
+// (starting at 1s, doubling up to a 10s cap) until it reports the job done,
+// ctx is cancelled, or deadline elapses. check returns (done, percent, err);
+// percent is forwarded to onProgress when non-nil.
+func pollUntilDone(ctx context.Context, onProgress func(percent int), check func(ctx context.Context) (done bool, percent int, err error)) error {
+	delay := time.Second
+	const maxDelay = 10 * time.Second
+
+	for {
+		done, percent, err := check(ctx)
+		if err != nil {
+			return err
+		}
+		if onProgress != nil {
+			onProgress(percent)
+		}
+		if done {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for job to complete: %w", ctx.Err())
+		case <-time.After(delay):
+		}
+
+		delay *= 2
+		if delay > maxDelay {
+			delay = maxDelay
+		}
+	}
+}
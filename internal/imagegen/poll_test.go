@@ -0,0 +1,55 @@
+package imagegen
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPollUntilDoneReturnsOnSuccess(t *testing.T) {
+	calls := 0
+	var progress []int
+	err := pollUntilDone(context.Background(), func(p int) { progress = append(progress, p) },
+		func(ctx context.Context) (bool, int, error) {
+			calls++
+			if calls < 2 {
+				return false, calls * 10, nil
+			}
+			return true, 100, nil
+		})
+	if err != nil {
+		t.Fatalf("pollUntilDone: %v", err)
+	}
+	if calls != 2 {
+		t.Fatalf("got %d calls, want 2", calls)
+	}
+	if len(progress) != 2 || progress[1] != 100 {
+		t.Fatalf("unexpected progress reports: %v", progress)
+	}
+}
+
+func TestPollUntilDoneReturnsCheckError(t *testing.T) {
+	wantErr := errors.New("job failed")
+	err := pollUntilDone(context.Background(), nil, func(ctx context.Context) (bool, int, error) {
+		return false, 0, wantErr
+	})
+	if !errors.Is(err, wantErr) {
+		t.Fatalf("got %v, want %v", err, wantErr)
+	}
+}
+
+func TestPollUntilDoneStopsAtContextDeadline(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := pollUntilDone(ctx, nil, func(ctx context.Context) (bool, int, error) {
+		return false, 0, nil
+	})
+	if err == nil {
+		t.Fatal("expected an error once the context deadline elapses")
+	}
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want a context.DeadlineExceeded-wrapping error", err)
+	}
+}
@@ -0,0 +1,105 @@
+package imagegen
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// StabilityBackend talks to the Stability AI v2beta image APIs
+// (https://api.stability.ai/v2beta/stable-image/...) using an API-key
+// bearer token. Stability returns a single image per call, so N is ignored.
+type StabilityBackend struct {
+	apiKey string
+	engine string
+	client *http.Client
+}
+
+// NewStabilityBackend constructs a StabilityBackend from cfg.
+func NewStabilityBackend(cfg Config) (*StabilityBackend, error) {
+	if cfg.StabilityAPIKey == "" {
+		return nil, fmt.Errorf("stability backend requires STABILITY_API_KEY")
+	}
+	engine := cfg.StabilityEngine
+	if engine == "" {
+		engine = "core"
+	}
+	return &StabilityBackend{
+		apiKey: cfg.StabilityAPIKey,
+		engine: engine,
+		client: &http.Client{Timeout: 5 * time.Minute},
+	}, nil
+}
+
+func (b *StabilityBackend) Name() string { return "stability" }
+
+// Generate calls the text-to-image endpoint (no input image).
+func (b *StabilityBackend) Generate(ctx context.Context, req GenerateRequest) (GenerateResponse, error) {
+	return b.call(ctx, "generate/"+b.engine, req)
+}
+
+// Edit calls the image-to-image endpoint, attaching the first input image.
+// Stability's edit API takes a single "image" field plus, for inpainting,
+// a "mask" field; it has no notion of a separate background image.
+func (b *StabilityBackend) Edit(ctx context.Context, req GenerateRequest) (GenerateResponse, error) {
+	op := "edit/inpaint"
+	if req.Mask == nil {
+		op = "generate/image-to-image"
+	}
+	return b.call(ctx, op, req)
+}
+
+func (b *StabilityBackend) call(ctx context.Context, op string, req GenerateRequest) (GenerateResponse, error) {
+	endpoint := "https://api.stability.ai/v2beta/stable-image/" + op
+
+	fields := map[string]string{
+		"prompt":        req.Prompt,
+		"output_format": "png",
+	}
+
+	body, contentType, err := buildMultipartBody(req.Images, req.Mask, fields)
+	if err != nil {
+		return GenerateResponse{}, fmt.Errorf("building request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, body)
+	if err != nil {
+		return GenerateResponse{}, fmt.Errorf("creating HTTP request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+b.apiKey)
+	httpReq.Header.Set("Content-Type", contentType)
+	httpReq.Header.Set("Accept", "application/json")
+
+	resp, err := b.client.Do(httpReq)
+	if err != nil {
+		return GenerateResponse{}, fmt.Errorf("sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return GenerateResponse{}, fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return GenerateResponse{}, &HTTPError{StatusCode: resp.StatusCode, Message: truncate(string(respBytes), 500)}
+	}
+
+	var result struct {
+		Image  string   `json:"image"`
+		Errors []string `json:"errors"`
+	}
+	if err := json.Unmarshal(respBytes, &result); err != nil {
+		return GenerateResponse{}, fmt.Errorf("parsing response JSON: %w (raw: %s)", err, truncate(string(respBytes), 500))
+	}
+	if len(result.Errors) > 0 {
+		return GenerateResponse{}, fmt.Errorf("stability %s failed: %v", op, result.Errors)
+	}
+	if result.Image == "" {
+		return GenerateResponse{}, fmt.Errorf("stability %s failed: no image data in response", op)
+	}
+
+	return GenerateResponse{Images: []GeneratedImage{{B64JSON: result.Image}}}, nil
+}
@@ -0,0 +1,66 @@
+package outputsink
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/blob"
+)
+
+// AzBlobSink uploads images to Azure Blob Storage using Azure AD bearer
+// tokens (DefaultAzureCredential), the same credential pattern used by
+// AzureBackend in internal/imagegen.
+type AzBlobSink struct {
+	container string
+	prefix    string
+	client    *azblob.Client
+}
+
+// NewAzBlobSink constructs an AzBlobSink targeting container in the
+// storage account named by the AZURE_STORAGE_ACCOUNT environment
+// variable, storing blobs under prefix (may be empty).
+func NewAzBlobSink(container, prefix string) (*AzBlobSink, error) {
+	if container == "" {
+		return nil, fmt.Errorf("az output requires a container name, e.g. -output az://my-container/prefix")
+	}
+	account := os.Getenv("AZURE_STORAGE_ACCOUNT")
+	if account == "" {
+		return nil, fmt.Errorf("az output requires AZURE_STORAGE_ACCOUNT to be set")
+	}
+
+	cred, err := azidentity.NewDefaultAzureCredential(nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating credential: %w", err)
+	}
+
+	serviceURL := fmt.Sprintf("https://%s.blob.core.windows.net/", account)
+	client, err := azblob.NewClient(serviceURL, cred, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating Azure Blob client: %w", err)
+	}
+
+	return &AzBlobSink{container: container, prefix: prefix, client: client}, nil
+}
+
+func (s *AzBlobSink) Put(ctx context.Context, name string, data []byte, meta Metadata) (string, error) {
+	blobName := ObjectKey(name)
+	if s.prefix != "" {
+		blobName = s.prefix + "/" + blobName
+	}
+
+	var opts *azblob.UploadBufferOptions
+	if meta.ContentType != "" {
+		opts = &azblob.UploadBufferOptions{
+			HTTPHeaders: &blob.HTTPHeaders{BlobContentType: &meta.ContentType},
+		}
+	}
+	_, err := s.client.UploadBuffer(ctx, s.container, blobName, data, opts)
+	if err != nil {
+		return "", fmt.Errorf("uploading to az://%s/%s: %w", s.container, blobName, err)
+	}
+
+	return fmt.Sprintf("%s%s/%s", s.client.URL(), s.container, blobName), nil
+}
@@ -0,0 +1,57 @@
+package outputsink
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+)
+
+// S3Sink uploads images to an S3-compatible object store using the
+// default AWS credential chain (environment variables, shared config,
+// instance role, etc.).
+type S3Sink struct {
+	bucket string
+	prefix string
+	client *s3.Client
+}
+
+// NewS3Sink constructs an S3Sink targeting bucket, storing objects under
+// prefix (may be empty).
+func NewS3Sink(bucket, prefix string) (*S3Sink, error) {
+	if bucket == "" {
+		return nil, fmt.Errorf("s3 output requires a bucket name, e.g. -output s3://my-bucket/prefix")
+	}
+	cfg, err := config.LoadDefaultConfig(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("loading AWS config: %w", err)
+	}
+	return &S3Sink{bucket: bucket, prefix: prefix, client: s3.NewFromConfig(cfg)}, nil
+}
+
+func (s *S3Sink) Put(ctx context.Context, name string, data []byte, meta Metadata) (string, error) {
+	key := ObjectKey(name)
+	if s.prefix != "" {
+		key = s.prefix + "/" + key
+	}
+
+	contentType := meta.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(data),
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return "", fmt.Errorf("uploading to s3://%s/%s: %w", s.bucket, key, err)
+	}
+
+	return fmt.Sprintf("https://%s.s3.amazonaws.com/%s", s.bucket, key), nil
+}
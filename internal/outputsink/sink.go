@@ -0,0 +1,96 @@
+// Package outputsink persists generated images to a destination chosen by
+// the user (local disk, S3, or Azure Blob Storage), so the CLI can slot
+// into asset pipelines that publish straight to a CDN instead of always
+// writing to the local filesystem.
+package outputsink
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Metadata describes a generated image, passed through to sinks that can
+// record it (e.g. as object tags or headers).
+type Metadata struct {
+	ContentType  string
+	Prompt       string
+	SourceImages []string
+}
+
+// Sink persists a generated image and returns the canonical URL (or local
+// path) the CLI should report to the user.
+type Sink interface {
+	Put(ctx context.Context, name string, data []byte, meta Metadata) (string, error)
+}
+
+// New returns the Sink named by rawDest's URL scheme:
+//
+//	""        local filesystem; name is used as-is (the CLI's existing behavior)
+//	file://   local filesystem; name is placed under the URL's path as a directory prefix
+//	s3://bucket/prefix      an S3-compatible object store
+//	az://container/prefix   Azure Blob Storage
+func New(rawDest string) (Sink, error) {
+	if rawDest == "" {
+		return &FileSink{}, nil
+	}
+
+	u, err := url.Parse(rawDest)
+	if err != nil {
+		return nil, fmt.Errorf("parsing -output %q: %w", rawDest, err)
+	}
+
+	switch u.Scheme {
+	case "file":
+		return &FileSink{Dir: u.Path}, nil
+	case "s3":
+		return NewS3Sink(u.Host, strings.TrimPrefix(u.Path, "/"))
+	case "az":
+		return NewAzBlobSink(u.Host, strings.TrimPrefix(u.Path, "/"))
+	default:
+		return nil, fmt.Errorf("unknown -output scheme %q (want file://, s3://, or az://)", u.Scheme)
+	}
+}
+
+// ObjectKey turns a local output path into a safe, collision-resistant
+// object key for remote sinks (S3, Azure Blob): it strips any leading
+// "/" or ".." segments and normalizes to "/"-separated components, but
+// otherwise preserves the path's directory structure so that two items
+// differing only by directory (e.g. "a/out.png" and "b/out.png") don't
+// flatten to the same key.
+func ObjectKey(name string) string {
+	clean := filepath.ToSlash(filepath.Clean(name))
+	clean = strings.TrimPrefix(clean, "/")
+	for strings.HasPrefix(clean, "../") {
+		clean = strings.TrimPrefix(clean, "../")
+	}
+	clean = strings.TrimPrefix(clean, "..")
+	return clean
+}
+
+// FileSink writes images to the local filesystem. With Dir empty (the
+// default when -output isn't set), name is treated as a full path,
+// matching the CLI's original behavior of writing straight to the
+// derived output file. With Dir set, name is rebased under Dir using
+// ObjectKey, so its directory structure (not just its base name) is
+// preserved the same way S3Sink/AzBlobSink preserve it in their keys.
+type FileSink struct {
+	Dir string
+}
+
+func (s *FileSink) Put(_ context.Context, name string, data []byte, _ Metadata) (string, error) {
+	path := name
+	if s.Dir != "" {
+		path = filepath.Join(s.Dir, filepath.FromSlash(ObjectKey(name)))
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("creating output directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return "", fmt.Errorf("writing output file: %w", err)
+	}
+	return path, nil
+}
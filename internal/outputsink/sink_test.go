@@ -0,0 +1,84 @@
+package outputsink
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFileSinkPutDefaultUsesNameAsPath(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "out.png")
+
+	sink := &FileSink{}
+	dest, err := sink.Put(context.Background(), path, []byte("data"), Metadata{})
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if dest != path {
+		t.Fatalf("got dest %q, want %q", dest, path)
+	}
+	if data, err := os.ReadFile(path); err != nil || string(data) != "data" {
+		t.Fatalf("ReadFile: data=%q err=%v", data, err)
+	}
+}
+
+func TestFileSinkPutWithDirRebasesNamePreservingSubdirs(t *testing.T) {
+	dir := t.TempDir()
+
+	sink := &FileSink{Dir: dir}
+	dest, err := sink.Put(context.Background(), "nested/out.png", []byte("data"), Metadata{})
+	if err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	want := filepath.Join(dir, "nested", "out.png")
+	if dest != want {
+		t.Fatalf("got dest %q, want %q", dest, want)
+	}
+	if data, err := os.ReadFile(want); err != nil || string(data) != "data" {
+		t.Fatalf("ReadFile: data=%q err=%v", data, err)
+	}
+}
+
+func TestFileSinkPutWithDirKeepsDifferentSubdirsSeparate(t *testing.T) {
+	dir := t.TempDir()
+	sink := &FileSink{Dir: dir}
+
+	if _, err := sink.Put(context.Background(), "a/out.png", []byte("a-data"), Metadata{}); err != nil {
+		t.Fatalf("Put a: %v", err)
+	}
+	if _, err := sink.Put(context.Background(), "b/out.png", []byte("b-data"), Metadata{}); err != nil {
+		t.Fatalf("Put b: %v", err)
+	}
+
+	aData, err := os.ReadFile(filepath.Join(dir, "a", "out.png"))
+	if err != nil || string(aData) != "a-data" {
+		t.Fatalf("a/out.png: data=%q err=%v", aData, err)
+	}
+	bData, err := os.ReadFile(filepath.Join(dir, "b", "out.png"))
+	if err != nil || string(bData) != "b-data" {
+		t.Fatalf("b/out.png: data=%q err=%v", bData, err)
+	}
+}
+
+func TestObjectKeyPreservesDirectoryStructure(t *testing.T) {
+	cases := map[string]string{
+		"out.png":           "out.png",
+		"a/out.png":         "a/out.png",
+		"b/out.png":         "b/out.png",
+		"/abs/path/out.png": "abs/path/out.png",
+		"../escape/out.png": "escape/out.png",
+		"a/../b/out.png":    "b/out.png",
+	}
+	for in, want := range cases {
+		if got := ObjectKey(in); got != want {
+			t.Errorf("ObjectKey(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestNewUnknownSchemeErrors(t *testing.T) {
+	if _, err := New("ftp://example.com/foo"); err == nil {
+		t.Fatal("expected an error for an unsupported scheme")
+	}
+}